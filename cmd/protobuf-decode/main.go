@@ -0,0 +1,185 @@
+// Command protobuf-decode decodes raw Protobuf wire-format data (optionally
+// gRPC/gRPC-Web framed, optionally against a declared schema) into a
+// human-readable or machine-readable rendering, and can round-trip that
+// rendering back into wire bytes. See pkg/wiredump for the underlying
+// library.
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"protobuf-decode/grpcweb"
+	"protobuf-decode/pkg/wiredump"
+	"protobuf-decode/schema"
+	"protobuf-decode/wiretext"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "path to a compiled FileDescriptorSet or a .proto file describing the message schema")
+	messageName := flag.String("message", "", "fully qualified name of the top-level message type (required with -schema)")
+	outputFormat := flag.String("o", "", "output format: the default pretty-prints the message; json|protojson emit structured output; text emits the round-trippable wiretext form; wire|hex|base64 re-encode the message as raw, hex, or base64 wire bytes")
+	inputFormat := flag.String("i", "auto", "input format: auto (default) decodes base64/hex wire bytes; text reads wiretext DSL source (prefix with @ to read from a file)")
+	encoding := flag.String("encoding", "gzip", "decompression used for compressed gRPC-Web/gRPC frames: gzip, deflate, or identity")
+	maxDecompressedBytes := flag.Int("max-decompressed-bytes", 64<<20, "reject a compressed gRPC-Web/gRPC frame if decompressing it would exceed this many bytes (0 disables the check)")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Println("Usage: protobuf-decode [-schema path -message Type] [-i auto|text] [-o text|wire|hex|base64|json|protojson] [-encoding gzip|deflate|identity] [-max-decompressed-bytes n] <input>")
+		os.Exit(1)
+	}
+
+	var resolver *schema.Resolver
+	if *schemaPath != "" {
+		r, err := schema.Load(*schemaPath)
+		if err != nil {
+			fmt.Printf("Error loading schema: %v\n", err)
+			os.Exit(1)
+		}
+		if *messageName == "" {
+			fmt.Println("Error: -message is required when -schema is set")
+			os.Exit(1)
+		}
+		resolver = r
+	}
+
+	decoder := wiredump.NewDecoder(wiredump.Options{Resolver: resolver, Message: *messageName, MaxDecompressedBytes: *maxDecompressedBytes})
+	input := flag.Arg(0)
+
+	// frame pairs each message/trailer frame with its decoded Fields (Fields
+	// is nil for trailer frames). For -i text there is always exactly one,
+	// synthetic, non-framed entry.
+	type frame struct {
+		grpcweb.Frame
+		fields wiredump.Fields
+	}
+	var frames []frame
+	var framed bool
+
+	switch *inputFormat {
+	case "auto":
+		msgBytes, err := wiredump.DecodeInput(input)
+		if err != nil {
+			fmt.Printf("Error decoding input: %v\n", err)
+			os.Exit(1)
+		}
+
+		var results []wiredump.FrameResult
+		results, framed, err = decoder.DecodeFrames(msgBytes, *encoding)
+		if err != nil {
+			fmt.Printf("Error parsing: %v\n", err)
+			os.Exit(1)
+		}
+		if framed {
+			fmt.Println("The input will be parsed as a gRPC-Web/gRPC stream")
+		}
+		for _, r := range results {
+			frames = append(frames, frame{Frame: r.Frame, fields: r.Fields})
+		}
+
+	case "text":
+		src := input
+		if strings.HasPrefix(src, "@") {
+			data, err := os.ReadFile(src[1:])
+			if err != nil {
+				fmt.Printf("Error reading %s: %v\n", src[1:], err)
+				os.Exit(1)
+			}
+			src = string(data)
+		}
+		wfields, err := wiretext.Unmarshal(src)
+		if err != nil {
+			fmt.Printf("Error parsing wiretext: %v\n", err)
+			os.Exit(1)
+		}
+		frames = append(frames, frame{fields: wiredump.FromWiretext(wfields)})
+
+	default:
+		fmt.Printf("Error: unknown input format %q\n", *inputFormat)
+		os.Exit(1)
+	}
+
+	switch *outputFormat {
+	case "":
+		if !framed {
+			fmt.Println("Decoded Protobuf Message:")
+			out, err := wiredump.Format(frames[0].fields, wiredump.FormatOptions{Style: wiredump.StyleText})
+			if err != nil {
+				fmt.Printf("Error formatting: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(out)
+			break
+		}
+		for i, f := range frames {
+			status := "message"
+			if f.Trailer {
+				status = "trailer"
+			} else if f.Compressed {
+				status = "compressed"
+			}
+			fmt.Printf("--- frame %d (%s, %d bytes) ---\n", i, status, f.RawLength)
+			if f.Trailer {
+				for k, v := range f.Trailers {
+					fmt.Printf("%s: %s\n", k, v)
+				}
+				continue
+			}
+			out, err := wiredump.Format(f.fields, wiredump.FormatOptions{Style: wiredump.StyleText})
+			if err != nil {
+				fmt.Printf("Error formatting: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Print(out)
+		}
+
+	case "json", "protojson":
+		if framed && len(frames) > 1 {
+			fmt.Printf("Error: -o %s does not support multi-frame input; re-run without -o to inspect each frame\n", *outputFormat)
+			os.Exit(1)
+		}
+		style := wiredump.StyleJSON
+		if *outputFormat == "protojson" {
+			style = wiredump.StyleProtoJSON
+		}
+		out, err := wiredump.Format(frames[0].fields, wiredump.FormatOptions{Style: style})
+		if err != nil {
+			fmt.Printf("Error formatting: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(out)
+
+	case "text", "wire", "hex", "base64":
+		if framed && len(frames) > 1 {
+			fmt.Printf("Error: -o %s does not support multi-frame input; re-run without -o to inspect each frame\n", *outputFormat)
+			os.Exit(1)
+		}
+		fields := frames[0].fields
+		switch *outputFormat {
+		case "text":
+			fmt.Print(wiretext.Marshal(wiredump.ToWiretext(fields)))
+			return
+		}
+		encoded, err := fields.Encode()
+		if err != nil {
+			fmt.Printf("Error encoding: %v\n", err)
+			os.Exit(1)
+		}
+		switch *outputFormat {
+		case "wire":
+			os.Stdout.Write(encoded)
+		case "hex":
+			fmt.Println(hex.EncodeToString(encoded))
+		case "base64":
+			fmt.Println(base64.StdEncoding.EncodeToString(encoded))
+		}
+
+	default:
+		fmt.Printf("Error: unknown output format %q\n", *outputFormat)
+		os.Exit(1)
+	}
+}
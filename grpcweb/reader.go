@@ -0,0 +1,173 @@
+// Package grpcweb implements the length-prefixed frame format shared by
+// gRPC-Web and raw HTTP/2 gRPC message bodies: a sequence of frames, each
+// consisting of a 1-byte flag, a 4-byte big-endian length, and a payload
+// that is either a message (optionally compressed) or, for gRPC-Web, a set
+// of HTTP/1.1-style trailers (grpc-status, grpc-message, ...).
+package grpcweb
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	flagCompressed = 0x01
+	flagTrailer    = 0x80
+)
+
+// Frame is one length-prefixed frame read from a gRPC/gRPC-Web body.
+type Frame struct {
+	// Compressed reports whether the frame's flag byte had the compression
+	// bit (bit 0) set.
+	Compressed bool
+	// Trailer reports whether the frame's flag byte had the trailer bit
+	// (bit 7, 0x80) set, meaning Trailers (not Payload) holds the frame's
+	// content.
+	Trailer bool
+	// Payload is the frame's decompressed message bytes. Unset when
+	// Trailer is true.
+	Payload []byte
+	// RawLength is the on-wire length of the frame's payload, before
+	// decompression.
+	RawLength int
+	// Trailers holds the parsed key/value pairs when Trailer is true.
+	Trailers map[string]string
+}
+
+// Decompressor decompresses a frame payload for a particular encoding.
+type Decompressor func(io.Reader) (io.Reader, error)
+
+// DefaultDecompressors are the Decompressors available by name out of the
+// box: "gzip", "deflate", and "identity" (a no-op, for already-decompressed
+// input misflagged as compressed).
+var DefaultDecompressors = map[string]Decompressor{
+	"identity": func(r io.Reader) (io.Reader, error) { return r, nil },
+	"gzip":     func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	"deflate":  func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil },
+}
+
+// Reader walks a gRPC/gRPC-Web body as a sequence of length-prefixed
+// frames, decompressing compressed message frames as it goes.
+type Reader struct {
+	data                 []byte
+	pos                  int
+	encoding             string
+	decompressors        map[string]Decompressor
+	maxDecompressedBytes int
+}
+
+// NewReader returns a Reader over data. encoding selects the decompressor
+// used for frames with the compression bit set (default "gzip" if empty);
+// decompressors defaults to DefaultDecompressors when nil. maxDecompressedBytes
+// caps how large a single frame's payload may grow once decompressed,
+// guarding against decompression bombs; zero means unlimited.
+func NewReader(data []byte, encoding string, decompressors map[string]Decompressor, maxDecompressedBytes int) *Reader {
+	if encoding == "" {
+		encoding = "gzip"
+	}
+	if decompressors == nil {
+		decompressors = DefaultDecompressors
+	}
+	return &Reader{data: data, encoding: encoding, decompressors: decompressors, maxDecompressedBytes: maxDecompressedBytes}
+}
+
+// Pos returns the number of input bytes consumed so far.
+func (r *Reader) Pos() int {
+	return r.pos
+}
+
+// Next reads the next frame, or returns io.EOF once the input is exhausted.
+func (r *Reader) Next() (Frame, error) {
+	if r.pos >= len(r.data) {
+		return Frame{}, io.EOF
+	}
+	if len(r.data)-r.pos < 5 {
+		return Frame{}, fmt.Errorf("grpcweb: truncated frame header at offset %d", r.pos)
+	}
+
+	flags := r.data[r.pos]
+	length := binary.BigEndian.Uint32(r.data[r.pos+1 : r.pos+5])
+	start := r.pos + 5
+	end := start + int(length)
+	if end > len(r.data) {
+		return Frame{}, fmt.Errorf("grpcweb: frame length %d at offset %d exceeds remaining data", length, r.pos)
+	}
+	raw := r.data[start:end]
+	r.pos = end
+
+	f := Frame{
+		Compressed: flags&flagCompressed != 0,
+		Trailer:    flags&flagTrailer != 0,
+		RawLength:  len(raw),
+	}
+
+	if f.Trailer {
+		f.Trailers = parseTrailers(raw)
+		return f, nil
+	}
+	if !f.Compressed {
+		f.Payload = raw
+		return f, nil
+	}
+
+	decompress, ok := r.decompressors[r.encoding]
+	if !ok {
+		return Frame{}, fmt.Errorf("grpcweb: unknown encoding %q", r.encoding)
+	}
+	dr, err := decompress(bytes.NewReader(raw))
+	if err != nil {
+		return Frame{}, fmt.Errorf("grpcweb: decompressing frame at offset %d: %w", start, err)
+	}
+	if r.maxDecompressedBytes > 0 {
+		dr = io.LimitReader(dr, int64(r.maxDecompressedBytes)+1)
+	}
+	payload, err := io.ReadAll(dr)
+	if err != nil {
+		return Frame{}, fmt.Errorf("grpcweb: decompressing frame at offset %d: %w", start, err)
+	}
+	if r.maxDecompressedBytes > 0 && len(payload) > r.maxDecompressedBytes {
+		return Frame{}, fmt.Errorf("grpcweb: decompressed frame at offset %d exceeds max decompressed bytes %d", start, r.maxDecompressedBytes)
+	}
+	f.Payload = payload
+	return f, nil
+}
+
+// ReadAll reads every frame in data, in order. It returns an error if any
+// frame's header is malformed, its length prefix doesn't fit the
+// remaining data, or a compressed frame's payload decompresses past
+// maxDecompressedBytes (zero means unlimited), so callers can fall back to
+// treating data as a single unframed message.
+func ReadAll(data []byte, encoding string, decompressors map[string]Decompressor, maxDecompressedBytes int) ([]Frame, error) {
+	r := NewReader(data, encoding, decompressors, maxDecompressedBytes)
+	var frames []Frame
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			return frames, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, f)
+	}
+}
+
+func parseTrailers(raw []byte) map[string]string {
+	trailers := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimRight(string(raw), "\r\n"), "\r\n") {
+		if line == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		trailers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return trailers
+}
@@ -0,0 +1,61 @@
+package grpcweb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("gzip.Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("gzip.Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func buildFrame(flags byte, payload []byte) []byte {
+	n := len(payload)
+	return append([]byte{flags, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}, payload...)
+}
+
+func TestReadAllCompressedFrame(t *testing.T) {
+	msg := []byte("a protobuf-shaped payload")
+	frame := buildFrame(flagCompressed, gzipBytes(t, msg))
+
+	frames, err := ReadAll(frame, "gzip", nil, 0)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(frames) != 1 || !frames[0].Compressed {
+		t.Fatalf("ReadAll() = %+v, want one compressed frame", frames)
+	}
+	if !bytes.Equal(frames[0].Payload, msg) {
+		t.Fatalf("ReadAll() payload = %q, want %q", frames[0].Payload, msg)
+	}
+}
+
+func TestReadAllUnknownEncoding(t *testing.T) {
+	frame := buildFrame(flagCompressed, gzipBytes(t, []byte("x")))
+
+	_, err := ReadAll(frame, "brotli", nil, 0)
+	if err == nil || !strings.Contains(err.Error(), `unknown encoding "brotli"`) {
+		t.Fatalf("ReadAll() error = %v, want it to mention the unknown encoding", err)
+	}
+}
+
+func TestReadAllDecompressionBomb(t *testing.T) {
+	msg := bytes.Repeat([]byte("A"), 1<<20)
+	frame := buildFrame(flagCompressed, gzipBytes(t, msg))
+
+	_, err := ReadAll(frame, "gzip", nil, 1024)
+	if err == nil || !strings.Contains(err.Error(), "exceeds max decompressed bytes") {
+		t.Fatalf("ReadAll() error = %v, want it to mention exceeding max decompressed bytes", err)
+	}
+}
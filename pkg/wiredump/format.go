@@ -0,0 +1,268 @@
+package wiredump
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"protobuf-decode/schema"
+)
+
+// Style selects how Format renders a decoded message.
+type Style string
+
+const (
+	// StyleText is the default human-readable indented rendering.
+	StyleText Style = "text"
+	// StyleJSON renders fields as generic JSON, keyed by tag number, with no
+	// schema required.
+	StyleJSON Style = "json"
+	// StyleProtoJSON renders fields as JSON keyed by their schema-declared
+	// field name, the way protojson would marshal the same message. Fields
+	// without a Desc (no schema match) fall back to their tag number.
+	StyleProtoJSON Style = "protojson"
+)
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	// Style selects the rendering. Defaults to StyleText when empty.
+	Style Style
+	// Indent is the indent unit used by StyleJSON and StyleProtoJSON, passed
+	// to json.MarshalIndent. Defaults to "  " when empty; pass a single
+	// space or similar to compact the output, since json.Marshal (no
+	// indent) is not exposed separately.
+	Indent string
+}
+
+// Format renders fields according to opts.
+func Format(fields Fields, opts FormatOptions) (string, error) {
+	switch opts.Style {
+	case "", StyleText:
+		return formatFields(fields, ""), nil
+	case StyleJSON:
+		return formatJSON(fields, opts, false)
+	case StyleProtoJSON:
+		return formatJSON(fields, opts, true)
+	default:
+		return "", fmt.Errorf("wiredump: unknown format style %q", opts.Style)
+	}
+}
+
+func formatJSON(fields Fields, opts FormatOptions, useSchemaNames bool) (string, error) {
+	indent := opts.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	v := jsonOf(fields, useSchemaNames)
+	b, err := json.MarshalIndent(v, "", indent)
+	if err != nil {
+		return "", fmt.Errorf("wiredump: marshaling JSON: %w", err)
+	}
+	return string(b), nil
+}
+
+// jsonOf converts Fields into a JSON-marshalable value. Repeated tags (or,
+// for protojson, repeated schema fields) collapse into a single array under
+// one key, matching how protojson renders a repeated field.
+func jsonOf(fields Fields, useSchemaNames bool) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, f := range fields {
+		key := strconv.Itoa(int(f.Tag))
+		if useSchemaNames && f.Desc != nil {
+			key = f.Desc.Name
+		}
+		v := jsonValueOf(f, useSchemaNames)
+		switch existing := out[key].(type) {
+		case nil:
+			out[key] = v
+		case []interface{}:
+			out[key] = append(existing, v)
+		default:
+			out[key] = []interface{}{existing, v}
+		}
+	}
+	return out
+}
+
+func jsonValueOf(f Field, useSchemaNames bool) interface{} {
+	switch v := f.Value.(type) {
+	case VarintValue:
+		return v.Raw
+	case Fixed32Value:
+		return v.Raw
+	case Fixed64Value:
+		return v.Raw
+	case Fields:
+		return jsonOf(v, useSchemaNames)
+	case Group:
+		return jsonOf(Fields(v), useSchemaNames)
+	case string:
+		return v
+	case []byte:
+		return v
+	case PackedRepeated:
+		values := make([]interface{}, len(v.Values))
+		for i, e := range v.Values {
+			values[i] = rawOf(e)
+		}
+		return values
+	case Candidates:
+		options := make([]interface{}, len(v.Options))
+		for i, o := range v.Options {
+			switch ov := o.(type) {
+			case PackedRepeated:
+				values := make([]interface{}, len(ov.Values))
+				for j, e := range ov.Values {
+					values[j] = rawOf(e)
+				}
+				options[i] = values
+			default:
+				options[i] = ov
+			}
+		}
+		return options
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formatPackedRepeated renders a PackedRepeated as e.g.
+// "packed<varint>[3]: {1, 150, 42}".
+func formatPackedRepeated(v PackedRepeated) string {
+	raws := make([]string, len(v.Values))
+	for i, e := range v.Values {
+		raws[i] = fmt.Sprintf("%v", rawOf(e))
+	}
+	return fmt.Sprintf("packed<%s>[%d]: {%s}", v.ElemType, len(v.Values), strings.Join(raws, ", "))
+}
+
+// rawOf returns the raw decoded value behind a VarintValue/Fixed32Value/
+// Fixed64Value, for the compact PackedRepeated rendering.
+func rawOf(e interface{}) interface{} {
+	switch ev := e.(type) {
+	case VarintValue:
+		return ev.Raw
+	case Fixed32Value:
+		return ev.Raw
+	case Fixed64Value:
+		return ev.Raw
+	}
+	return e
+}
+
+// formatFields recursively formats decoded fields into a human-readable string.
+func formatFields(fields Fields, indent string) string {
+	var builder strings.Builder
+	for _, field := range fields {
+		if field.Desc != nil {
+			builder.WriteString(formatSchemaField(field, indent))
+			continue
+		}
+		if g, ok := field.Value.(Group); ok {
+			builder.WriteString(fmt.Sprintf("%sgroup Tag %d {\n", indent, field.Tag))
+			builder.WriteString(formatFields(Fields(g), indent+"  "))
+			builder.WriteString(fmt.Sprintf("%s}\n", indent))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%sTag %d (%s): ", indent, field.Tag, typeNames[field.Type]))
+
+		switch v := field.Value.(type) {
+		case VarintValue:
+			builder.WriteString("{\n")
+			builder.WriteString(fmt.Sprintf("%s  %s\n", indent, strings.Join(v.Interpretations, ", ")))
+			builder.WriteString(indent + "}\n")
+		case Fixed32Value:
+			builder.WriteString("{\n")
+			builder.WriteString(fmt.Sprintf("%s  %s\n", indent, strings.Join(v.Interpretations, ", ")))
+			builder.WriteString(indent + "}\n")
+		case Fixed64Value:
+			builder.WriteString("{\n")
+			builder.WriteString(fmt.Sprintf("%s  %s\n", indent, strings.Join(v.Interpretations, ", ")))
+			builder.WriteString(indent + "}\n")
+		case string:
+			builder.WriteString(fmt.Sprintf("string: %q\n", v))
+		case []byte:
+			builder.WriteString(fmt.Sprintf("[]byte: %x (raw bytes)\n", v))
+		case PackedRepeated:
+			builder.WriteString(formatPackedRepeated(v) + "\n")
+		case Candidates:
+			builder.WriteString("candidates {\n")
+			for _, c := range v.Options {
+				switch cv := c.(type) {
+				case string:
+					builder.WriteString(fmt.Sprintf("%s  string: %q\n", indent, cv))
+				case PackedRepeated:
+					builder.WriteString(fmt.Sprintf("%s  %s\n", indent, formatPackedRepeated(cv)))
+				}
+			}
+			builder.WriteString(indent + "}\n")
+		case Fields:
+			builder.WriteString("Message {\n")
+			builder.WriteString(formatFields(v, indent+"  "))
+			builder.WriteString(fmt.Sprintf("%s}\n", indent))
+		default:
+			builder.WriteString(fmt.Sprintf("Unknown type: %T\n", v))
+		}
+	}
+	return builder.String()
+}
+
+// formatSchemaField renders a single field using its schema.Descriptor,
+// showing the declared name, cardinality, and type instead of the bare tag
+// number and wire type.
+func formatSchemaField(field Field, indent string) string {
+	var builder strings.Builder
+	cardinality := "optional"
+	if field.Desc.Repeated {
+		cardinality = "repeated"
+	}
+
+	if v, ok := field.Value.(Fields); ok {
+		typeName := field.Desc.MessageType
+		if typeName == "" {
+			typeName = "message"
+		}
+		builder.WriteString(fmt.Sprintf("%s%s %s %s = %d {\n", indent, cardinality, typeName, field.Desc.Name, field.Tag))
+		builder.WriteString(formatFields(v, indent+"  "))
+		builder.WriteString(fmt.Sprintf("%s}\n", indent))
+		return builder.String()
+	}
+
+	if g, ok := field.Value.(Group); ok {
+		builder.WriteString(fmt.Sprintf("%sgroup %s = %d {\n", indent, field.Desc.Name, field.Tag))
+		builder.WriteString(formatFields(Fields(g), indent+"  "))
+		builder.WriteString(fmt.Sprintf("%s}\n", indent))
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("%s%s %s %s = %d: ", indent, cardinality, schema.TypeName(field.Desc.Type), field.Desc.Name, field.Tag))
+	switch v := field.Value.(type) {
+	case VarintValue:
+		builder.WriteString(fmt.Sprintf("{%s}\n", strings.Join(v.Interpretations, ", ")))
+	case Fixed32Value:
+		builder.WriteString(fmt.Sprintf("{%s}\n", strings.Join(v.Interpretations, ", ")))
+	case Fixed64Value:
+		builder.WriteString(fmt.Sprintf("{%s}\n", strings.Join(v.Interpretations, ", ")))
+	case string:
+		builder.WriteString(fmt.Sprintf("%q\n", v))
+	case []byte:
+		builder.WriteString(fmt.Sprintf("%x (raw bytes)\n", v))
+	case PackedRepeated:
+		builder.WriteString(formatPackedRepeated(v) + "\n")
+	case Candidates:
+		builder.WriteString("candidates {\n")
+		for _, c := range v.Options {
+			switch cv := c.(type) {
+			case string:
+				builder.WriteString(fmt.Sprintf("%s  string: %q\n", indent, cv))
+			case PackedRepeated:
+				builder.WriteString(fmt.Sprintf("%s  %s\n", indent, formatPackedRepeated(cv)))
+			}
+		}
+		builder.WriteString(indent + "}\n")
+	default:
+		builder.WriteString(fmt.Sprintf("%v\n", v))
+	}
+	return builder.String()
+}
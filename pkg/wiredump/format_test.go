@@ -0,0 +1,30 @@
+package wiredump
+
+import (
+	"strings"
+	"testing"
+
+	"protobuf-decode/schema"
+)
+
+// TestFormatSchemaFieldGroup guards against formatSchemaField falling
+// through to the generic default branch (a raw Go struct dump) for a group
+// field that also has a schema Desc attached.
+func TestFormatSchemaFieldGroup(t *testing.T) {
+	field := Field{
+		Tag:  1,
+		Type: 3,
+		Desc: &schema.Descriptor{Name: "grp"},
+		Value: Group{
+			{Tag: 2, Type: 0, Value: VarintValue{Raw: uint64(42), Interpretations: []string{"[uint64]: 42"}}},
+		},
+	}
+
+	out := formatSchemaField(field, "")
+	if !strings.Contains(out, "group grp = 1 {") {
+		t.Fatalf("formatSchemaField() = %q, want it to render as a group block", out)
+	}
+	if strings.Contains(out, "%!") || strings.Contains(out, "<nil>") {
+		t.Fatalf("formatSchemaField() = %q, looks like a raw Go struct dump", out)
+	}
+}
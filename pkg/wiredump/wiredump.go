@@ -0,0 +1,531 @@
+// Package wiredump implements the reusable core of protobuf-decode: parsing
+// raw Protobuf wire-format bytes into a generic Field tree, without
+// assuming any particular message schema. It is safe to embed in other
+// tools (test fixtures, fuzzers, HTTP proxies, wireshark-style dissectors)
+// via Decoder, which also guards against malicious or malformed input with
+// configurable depth and size limits.
+package wiredump
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"protobuf-decode/grpcweb"
+	"protobuf-decode/schema"
+)
+
+// ErrMaxDepthExceeded is wrapped by the error parseMessage returns once
+// recursion passes Options.MaxDepth, so callers recursing into a
+// length-delimited field's bytes can tell "this isn't a nested message"
+// apart from "this is a nested message, but the bomb guard tripped" — the
+// latter must propagate rather than be swallowed as a fallback to
+// classifyBytes.
+var ErrMaxDepthExceeded = errors.New("wiredump: exceeded max nesting depth")
+
+var typeNames = map[protowire.Type]string{
+	protowire.VarintType:     "Varint",
+	protowire.Fixed32Type:    "Fixed32",
+	protowire.Fixed64Type:    "Fixed64",
+	protowire.BytesType:      "Bytes",
+	protowire.StartGroupType: "StartGroup",
+	protowire.EndGroupType:   "EndGroup",
+}
+
+// DecodeInput decodes s, a base64 or hex encoded string, into raw bytes.
+func DecodeInput(s string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err == nil {
+		return data, nil
+	}
+	data, err = hex.DecodeString(s)
+	if err == nil {
+		return data, nil
+	}
+	return nil, fmt.Errorf("failed to decode input as base64 or hex")
+}
+
+// Field represents a decoded Protobuf field with its tag, wire type, and
+// value. Value is one of VarintValue, Fixed32Value, Fixed64Value, Fields
+// (a nested message), Group, string, []byte, PackedRepeated, or
+// Candidates.
+type Field struct {
+	Tag   protowire.Number
+	Type  protowire.Type
+	Value interface{}
+
+	// Desc is the schema-declared descriptor for this field, set only when
+	// decoding was performed with a schema.Resolver. When nil, Format falls
+	// back to the tag-number/wire-type heuristics.
+	Desc *schema.Descriptor
+}
+
+// Fields is a decoded message: a sequence of fields, rendered by Format and
+// re-serializable back to wire format via Encode.
+type Fields []Field
+
+// Group is the value of a field parsed from a proto2 StartGroup/EndGroup
+// pair, distinct from Fields (a length-delimited nested message) so Format
+// and Fields.Encode can tell them apart.
+type Group Fields
+
+// VarintValue holds a decoded varint together with its possible
+// interpretations. Raw is kept alongside the display strings so the field
+// can be losslessly re-encoded (see Fields.Encode).
+type VarintValue struct {
+	Raw             uint64
+	Interpretations []string
+}
+
+// Fixed32Value holds a decoded 32-bit fixed value together with its
+// possible interpretations. Raw is kept alongside the display strings so
+// the field can be losslessly re-encoded (see Fields.Encode).
+type Fixed32Value struct {
+	Raw             uint32
+	Interpretations []string
+}
+
+// Fixed64Value holds a decoded 64-bit fixed value together with its
+// possible interpretations. Raw is kept alongside the display strings so
+// the field can be losslessly re-encoded (see Fields.Encode).
+type Fixed64Value struct {
+	Raw             uint64
+	Interpretations []string
+}
+
+// PackedRepeated is the value of a length-delimited field that, instead of
+// a nested message, decodes cleanly as a packed repeated scalar: a
+// back-to-back run of varints, fixed32s, or fixed64s with no leftover
+// bytes. Raw preserves the original bytes so the field can still be
+// losslessly re-encoded.
+type PackedRepeated struct {
+	ElemType string // "varint", "fixed32", or "fixed64"
+	Values   []interface{}
+	Raw      []byte
+}
+
+// Candidates holds every plausible interpretation of a length-delimited
+// field's bytes when more than one applies (e.g. the bytes are both a
+// valid UTF-8 string and a valid packed varint list), so none is silently
+// discarded. Options is a mix of string and PackedRepeated values. Raw
+// preserves the original bytes so the field can still be losslessly
+// re-encoded.
+type Candidates struct {
+	Options []interface{}
+	Raw     []byte
+}
+
+// Encode re-serializes fs into Protobuf wire format. Every field's Value
+// must still carry the raw numeric or byte value produced by a Decoder
+// (VarintValue, Fixed32Value, Fixed64Value, Fields, Group, string, []byte,
+// PackedRepeated, or Candidates); interpretation-only values cannot be
+// re-encoded.
+func (fs Fields) Encode() ([]byte, error) {
+	var out []byte
+	for _, f := range fs {
+		out = protowire.AppendTag(out, f.Tag, f.Type)
+		switch v := f.Value.(type) {
+		case VarintValue:
+			out = protowire.AppendVarint(out, v.Raw)
+		case Fixed32Value:
+			out = protowire.AppendFixed32(out, v.Raw)
+		case Fixed64Value:
+			out = protowire.AppendFixed64(out, v.Raw)
+		case Fields:
+			sub, err := v.Encode()
+			if err != nil {
+				return nil, fmt.Errorf("encoding tag %d: %w", f.Tag, err)
+			}
+			out = protowire.AppendBytes(out, sub)
+		case Group:
+			sub, err := Fields(v).Encode()
+			if err != nil {
+				return nil, fmt.Errorf("encoding group tag %d: %w", f.Tag, err)
+			}
+			out = append(out, sub...)
+			out = protowire.AppendTag(out, f.Tag, protowire.EndGroupType)
+		case string:
+			out = protowire.AppendBytes(out, []byte(v))
+		case []byte:
+			out = protowire.AppendBytes(out, v)
+		case PackedRepeated:
+			out = protowire.AppendBytes(out, v.Raw)
+		case Candidates:
+			out = protowire.AppendBytes(out, v.Raw)
+		default:
+			return nil, fmt.Errorf("cannot encode tag %d: unsupported value type %T", f.Tag, v)
+		}
+	}
+	return out, nil
+}
+
+// defaultMaxDepth bounds recursion into nested messages/groups when Options
+// doesn't set MaxDepth, so a maliciously or accidentally deeply-nested
+// message can't exhaust the stack.
+const defaultMaxDepth = 100
+
+// Options configures a Decoder.
+type Options struct {
+	// MaxDepth bounds recursion into nested messages and groups. Defaults
+	// to defaultMaxDepth when zero.
+	MaxDepth int
+	// MaxBytes rejects input larger than this many bytes outright. Zero
+	// means unlimited.
+	MaxBytes int
+	// MaxDecompressedBytes caps how large a single gRPC/gRPC-Web frame's
+	// payload may grow once decompressed by the default Framer, guarding
+	// against decompression bombs. Zero means unlimited. Ignored if Framer
+	// is set explicitly.
+	MaxDecompressedBytes int
+	// SkipNestedMessage disables the default heuristic of speculatively
+	// parsing a length-delimited field with no schema information as a
+	// nested message before falling back to string/packed-scalar/bytes.
+	SkipNestedMessage bool
+	// Resolver, when set, resolves field tags against a schema so Decode
+	// can attach a Descriptor to each Field. Message names the top-level
+	// message type and is required when Resolver is set.
+	Resolver *schema.Resolver
+	Message  string
+	// Framer splits input into frames before decoding each one as a
+	// message, e.g. grpcweb.ReadAll for gRPC/gRPC-Web framing. Defaults to
+	// grpcweb.ReadAll via DecodeFrames; Decode always treats input as a
+	// single unframed message.
+	Framer func(data []byte, encoding string) ([]grpcweb.Frame, error)
+}
+
+// Decoder parses Protobuf wire-format bytes into Fields according to
+// Options.
+type Decoder struct {
+	opts Options
+}
+
+// NewDecoder returns a Decoder configured by opts.
+func NewDecoder(opts Options) *Decoder {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = defaultMaxDepth
+	}
+	if opts.Framer == nil {
+		opts.Framer = func(data []byte, encoding string) ([]grpcweb.Frame, error) {
+			return grpcweb.ReadAll(data, encoding, nil, opts.MaxDecompressedBytes)
+		}
+	}
+	return &Decoder{opts: opts}
+}
+
+// Decode parses data as a single Protobuf message.
+func (d *Decoder) Decode(data []byte) (Fields, error) {
+	if d.opts.MaxBytes > 0 && len(data) > d.opts.MaxBytes {
+		return nil, fmt.Errorf("wiredump: input size %d exceeds max bytes %d", len(data), d.opts.MaxBytes)
+	}
+	fields, _, err := d.parseMessage(data, d.opts.Message, 0)
+	return fields, err
+}
+
+// FrameResult pairs a gRPC/gRPC-Web frame with its decoded message, when it
+// isn't a trailer frame.
+type FrameResult struct {
+	grpcweb.Frame
+	Fields Fields
+}
+
+// DecodeFrames splits data into frames via Options.Framer (gRPC/gRPC-Web
+// framing by default) and decodes each non-trailer frame's payload. If data
+// doesn't parse as a well-formed sequence of frames, it returns a single
+// FrameResult decoding data as one unframed message, and framed is false.
+func (d *Decoder) DecodeFrames(data []byte, encoding string) (results []FrameResult, framed bool, err error) {
+	if d.opts.MaxBytes > 0 && len(data) > d.opts.MaxBytes {
+		return nil, false, fmt.Errorf("wiredump: input size %d exceeds max bytes %d", len(data), d.opts.MaxBytes)
+	}
+
+	frames, ferr := d.opts.Framer(data, encoding)
+	if ferr != nil || len(frames) == 0 {
+		fields, derr := d.Decode(data)
+		if derr != nil {
+			return nil, false, derr
+		}
+		return []FrameResult{{Fields: fields}}, false, nil
+	}
+
+	for _, f := range frames {
+		fr := FrameResult{Frame: f}
+		if !f.Trailer {
+			fr.Fields, err = d.Decode(f.Payload)
+			if err != nil {
+				return nil, true, err
+			}
+		}
+		results = append(results, fr)
+	}
+	return results, true, nil
+}
+
+// parseGroup consumes a StartGroup field's body starting at remaining,
+// which begins just after the StartGroup tag for tagNum. It validates the
+// group is properly terminated with a matching EndGroup tag via
+// protowire.ConsumeGroup, then parses the body (everything up to the
+// EndGroup tag) as ordinary fields, resolved against message (the group's
+// declared message type, or "" if the group's own Descriptor wasn't
+// resolved).
+func (d *Decoder) parseGroup(remaining []byte, tagNum protowire.Number, message string, depth int) (Group, int, error) {
+	body, n := protowire.ConsumeGroup(tagNum, remaining)
+	if n < 0 {
+		return nil, 0, fmt.Errorf("unterminated or mismatched group for tag %d", tagNum)
+	}
+	fields, consumed, err := d.parseMessage(body, message, depth+1)
+	if err != nil || consumed != len(body) {
+		return nil, 0, fmt.Errorf("invalid group body for tag %d: %w", tagNum, err)
+	}
+	return Group(fields), n, nil
+}
+
+// parseMessage recursively parses Protobuf wire-format data into Fields. If
+// message is non-empty and d.opts.Resolver is set, each field's tag is
+// resolved against that message type and a Descriptor is attached.
+func (d *Decoder) parseMessage(data []byte, message string, depth int) (Fields, int, error) {
+	if depth > d.opts.MaxDepth {
+		return nil, 0, fmt.Errorf("%w %d (nested payload of %d bytes)", ErrMaxDepthExceeded, d.opts.MaxDepth, len(data))
+	}
+
+	var fields Fields
+	remaining := data
+	totalConsumed := 0
+
+	for len(remaining) > 0 {
+		tagNum, wireType, n := protowire.ConsumeTag(remaining)
+		if n < 0 {
+			return nil, totalConsumed, fmt.Errorf("invalid tag at offset %d", len(data)-len(remaining))
+		}
+		remaining = remaining[n:]
+		totalConsumed += n
+
+		var desc schema.Descriptor
+		var known bool
+		if d.opts.Resolver != nil && message != "" {
+			desc, known = d.opts.Resolver.Field(message, tagNum)
+		}
+
+		var value interface{}
+		consumed := 0
+
+		switch wireType {
+		case protowire.VarintType:
+			v, cn := protowire.ConsumeVarint(remaining)
+			if cn < 0 {
+				return nil, totalConsumed, fmt.Errorf("invalid varint at offset %d", len(data)-len(remaining))
+			}
+			value = interpretVarint(v)
+			consumed = cn
+
+		case protowire.Fixed32Type:
+			v, cn := protowire.ConsumeFixed32(remaining)
+			if cn < 0 {
+				return nil, totalConsumed, fmt.Errorf("invalid fixed32 at offset %d", len(data)-len(remaining))
+			}
+			value = interpretFixed32(v)
+			consumed = cn
+
+		case protowire.Fixed64Type:
+			v, cn := protowire.ConsumeFixed64(remaining)
+			if cn < 0 {
+				return nil, totalConsumed, fmt.Errorf("invalid fixed64 at offset %d", len(data)-len(remaining))
+			}
+			value = interpretFixed64(v)
+			consumed = cn
+
+		case protowire.BytesType:
+			v, cn := protowire.ConsumeBytes(remaining)
+			if cn < 0 {
+				return nil, totalConsumed, fmt.Errorf("invalid bytes at offset %d", len(data)-len(remaining))
+			}
+
+			switch {
+			case known && desc.MessageType != "" && d.opts.Resolver.HasMessage(desc.MessageType):
+				subFields, _, err := d.parseMessage(v, desc.MessageType, depth+1)
+				if err != nil {
+					return nil, totalConsumed, fmt.Errorf("parsing nested message %s at offset %d: %w", desc.MessageType, len(data)-len(remaining), err)
+				}
+				value = subFields
+			case known:
+				// The schema resolved this field as a scalar (or an
+				// unresolvable message type); trust that instead of
+				// re-running the guess heuristic below, which could
+				// misrender scalar bytes that happen to parse as a
+				// submessage.
+				value = classifyBytes(v)
+			case d.opts.SkipNestedMessage:
+				value = classifyBytes(v)
+			default:
+				if subFields, subConsumed, err := d.parseMessage(v, "", depth+1); err == nil && subConsumed == len(v) {
+					value = subFields
+				} else if errors.Is(err, ErrMaxDepthExceeded) {
+					return nil, totalConsumed, err
+				} else {
+					value = classifyBytes(v)
+				}
+			}
+			consumed = cn
+
+		case protowire.StartGroupType:
+			groupMessage := ""
+			if known {
+				groupMessage = desc.MessageType
+			}
+			g, n, err := d.parseGroup(remaining, tagNum, groupMessage, depth)
+			if err != nil {
+				return nil, totalConsumed, fmt.Errorf("%w at offset %d", err, len(data)-len(remaining))
+			}
+			value = g
+			consumed = n
+
+		case protowire.EndGroupType:
+			return nil, totalConsumed, fmt.Errorf("unexpected end group tag for field %d at offset %d", tagNum, len(data)-len(remaining))
+
+		default:
+			return nil, totalConsumed, fmt.Errorf("unsupported wire type %d at offset %d", wireType, len(data)-len(remaining))
+		}
+
+		remaining = remaining[consumed:]
+		totalConsumed += consumed
+
+		field := Field{Tag: tagNum, Type: wireType, Value: value}
+		if known {
+			d := desc
+			field.Desc = &d
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, totalConsumed, nil
+}
+
+// zigZagDecode32 decodes a ZigZag-encoded 32-bit value.
+func zigZagDecode32(n uint32) int32 {
+	return int32(n>>1) ^ -int32(n&1)
+}
+
+// zigZagDecode64 decodes a ZigZag-encoded 64-bit value.
+func zigZagDecode64(n uint64) int64 {
+	return int64(n>>1) ^ -int64(n&1)
+}
+
+func interpretVarint(v uint64) VarintValue {
+	var interpretations []string
+	interpretations = append(interpretations, fmt.Sprintf("[uint64]: %v", v))
+	interpretations = append(interpretations, fmt.Sprintf("[uint32]: %v", uint32(v)))
+	interpretations = append(interpretations, fmt.Sprintf("[int64]: %v", int64(v)))
+	interpretations = append(interpretations, fmt.Sprintf("[int32]: %v", int32(v)))
+	interpretations = append(interpretations, fmt.Sprintf("[sint64]: %v", zigZagDecode64(v)))
+	interpretations = append(interpretations, fmt.Sprintf("[sint32]: %v", zigZagDecode32(uint32(v))))
+	if v == 0 {
+		interpretations = append(interpretations, fmt.Sprintf("[bool]: %v", false))
+	} else if v == 1 {
+		interpretations = append(interpretations, fmt.Sprintf("[bool]: %v", true))
+	}
+	interpretations = append(interpretations, fmt.Sprintf("[enum]: %v", v))
+	return VarintValue{Raw: v, Interpretations: interpretations}
+}
+
+func interpretFixed32(v uint32) Fixed32Value {
+	var interpretations []string
+	interpretations = append(interpretations, fmt.Sprintf("[fixed32]: %v", v))
+	interpretations = append(interpretations, fmt.Sprintf("[float]: %v", math.Float32frombits(v)))
+	interpretations = append(interpretations, fmt.Sprintf("[sfixed32]: %v", int32(v)))
+	return Fixed32Value{Raw: v, Interpretations: interpretations}
+}
+
+func interpretFixed64(v uint64) Fixed64Value {
+	var interpretations []string
+	interpretations = append(interpretations, fmt.Sprintf("[fixed64]: %v", v))
+	interpretations = append(interpretations, fmt.Sprintf("[double]: %v", math.Float64frombits(v)))
+	interpretations = append(interpretations, fmt.Sprintf("[sfixed64]: %v", int64(v)))
+	return Fixed64Value{Raw: v, Interpretations: interpretations}
+}
+
+// tryPacked attempts to interpret v as a packed repeated scalar: a stream
+// of varints, and (when the length allows) a stream of fixed32s or
+// fixed64s. An interpretation is only returned if it consumes the entire
+// buffer and yields more than one element.
+func tryPacked(v []byte) []PackedRepeated {
+	var results []PackedRepeated
+
+	if elems, ok := tryPackedVarint(v); ok {
+		results = append(results, PackedRepeated{ElemType: "varint", Values: elems, Raw: v})
+	}
+	if len(v) > 0 && len(v)%4 == 0 {
+		if elems, ok := tryPackedFixed32(v); ok {
+			results = append(results, PackedRepeated{ElemType: "fixed32", Values: elems, Raw: v})
+		}
+	}
+	if len(v) > 0 && len(v)%8 == 0 {
+		if elems, ok := tryPackedFixed64(v); ok {
+			results = append(results, PackedRepeated{ElemType: "fixed64", Values: elems, Raw: v})
+		}
+	}
+	return results
+}
+
+func tryPackedVarint(v []byte) ([]interface{}, bool) {
+	var elems []interface{}
+	remaining := v
+	for len(remaining) > 0 {
+		n, cn := protowire.ConsumeVarint(remaining)
+		if cn < 0 {
+			return nil, false
+		}
+		elems = append(elems, interpretVarint(n))
+		remaining = remaining[cn:]
+	}
+	return elems, len(elems) > 1
+}
+
+func tryPackedFixed32(v []byte) ([]interface{}, bool) {
+	var elems []interface{}
+	for i := 0; i+4 <= len(v); i += 4 {
+		n, cn := protowire.ConsumeFixed32(v[i:])
+		if cn < 0 {
+			return nil, false
+		}
+		elems = append(elems, interpretFixed32(n))
+	}
+	return elems, len(elems) > 1
+}
+
+func tryPackedFixed64(v []byte) ([]interface{}, bool) {
+	var elems []interface{}
+	for i := 0; i+8 <= len(v); i += 8 {
+		n, cn := protowire.ConsumeFixed64(v[i:])
+		if cn < 0 {
+			return nil, false
+		}
+		elems = append(elems, interpretFixed64(n))
+	}
+	return elems, len(elems) > 1
+}
+
+// classifyBytes decides how to render a length-delimited field's bytes once
+// it's known not to be a nested message: as a bare string, a packed
+// repeated scalar, raw bytes, or — when more than one of those applies — a
+// Candidates set covering all of them.
+func classifyBytes(v []byte) interface{} {
+	var options []interface{}
+	if utf8.Valid(v) {
+		options = append(options, string(v))
+	}
+	for _, p := range tryPacked(v) {
+		options = append(options, p)
+	}
+
+	switch len(options) {
+	case 0:
+		return v
+	case 1:
+		return options[0]
+	default:
+		return Candidates{Options: options, Raw: v}
+	}
+}
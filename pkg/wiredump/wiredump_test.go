@@ -0,0 +1,259 @@
+package wiredump
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"protobuf-decode/schema"
+)
+
+func TestDecodeMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name:    "truncated tag",
+			data:    []byte{0x80},
+			wantErr: "invalid tag",
+		},
+		{
+			name:    "truncated varint value",
+			data:    protowire.AppendTag(nil, 1, protowire.VarintType),
+			wantErr: "invalid varint",
+		},
+		{
+			name:    "truncated fixed32 value",
+			data:    protowire.AppendTag(nil, 1, protowire.Fixed32Type),
+			wantErr: "invalid fixed32",
+		},
+		{
+			name:    "bytes length exceeds remaining data",
+			data:    append(protowire.AppendTag(nil, 1, protowire.BytesType), 0x05, 0x01, 0x02),
+			wantErr: "invalid bytes",
+		},
+		{
+			name:    "unterminated group",
+			data:    protowire.AppendTag(nil, 1, protowire.StartGroupType),
+			wantErr: "unterminated or mismatched group",
+		},
+		{
+			name: "mismatched end group tag",
+			data: append(
+				protowire.AppendTag(nil, 1, protowire.StartGroupType),
+				protowire.AppendTag(nil, 2, protowire.EndGroupType)...,
+			),
+			wantErr: "unterminated or mismatched group",
+		},
+		{
+			name:    "unexpected end group with no matching start",
+			data:    protowire.AppendTag(nil, 1, protowire.EndGroupType),
+			wantErr: "unexpected end group",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder(Options{})
+			_, err := d.Decode(tt.data)
+			if err == nil {
+				t.Fatalf("Decode(%x) succeeded, want error containing %q", tt.data, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Decode(%x) error = %q, want it to contain %q", tt.data, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// nestedMessage builds a message with a single bytes field (tag 1) nested
+// depth levels deep, each level itself a single-field message with a
+// bytes payload, bottoming out on a tiny varint field.
+func nestedMessage(depth int) []byte {
+	body := protowire.AppendTag(nil, 1, protowire.VarintType)
+	body = protowire.AppendVarint(body, 42)
+	for i := 0; i < depth; i++ {
+		wrapped := protowire.AppendTag(nil, 1, protowire.BytesType)
+		wrapped = protowire.AppendBytes(wrapped, body)
+		body = wrapped
+	}
+	return body
+}
+
+func TestDecodeMaxDepthBomb(t *testing.T) {
+	d := NewDecoder(Options{MaxDepth: 10})
+
+	if _, err := d.Decode(nestedMessage(5)); err != nil {
+		t.Fatalf("Decode with depth 5 under limit 10: unexpected error: %v", err)
+	}
+
+	_, err := d.Decode(nestedMessage(1000))
+	if err == nil {
+		t.Fatal("Decode with depth 1000 under limit 10: expected max depth error, got nil")
+	}
+	if !strings.Contains(err.Error(), "exceeded max nesting depth") {
+		t.Fatalf("Decode with depth 1000: error = %q, want it to mention max nesting depth", err.Error())
+	}
+}
+
+func TestDecodeMaxBytes(t *testing.T) {
+	data := protowire.AppendTag(nil, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, 150)
+
+	d := NewDecoder(Options{MaxBytes: len(data) - 1})
+	_, err := d.Decode(data)
+	if err == nil || !strings.Contains(err.Error(), "exceeds max bytes") {
+		t.Fatalf("Decode() error = %v, want it to mention exceeding max bytes", err)
+	}
+}
+
+// TestDecodeSchemaScalarNotMistakenForMessage guards against treating a
+// schema-declared scalar field as a submessage just because its bytes
+// happen to parse cleanly as one (e.g. a string field whose contents are
+// "Tag(2,Varint) Varint(42)").
+func TestDecodeSchemaScalarNotMistakenForMessage(t *testing.T) {
+	proto := filepath.Join(t.TempDir(), "pkg.proto")
+	src := `syntax = "proto3"; package pkg; message M { string name = 1; }`
+	if err := os.WriteFile(proto, []byte(src), 0o644); err != nil {
+		t.Fatalf("writing test .proto: %v", err)
+	}
+	resolver, err := schema.Load(proto)
+	if err != nil {
+		t.Fatalf("schema.Load() error = %v", err)
+	}
+
+	body := protowire.AppendTag(nil, 2, protowire.VarintType)
+	body = protowire.AppendVarint(body, 42)
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendBytes(data, body)
+
+	d := NewDecoder(Options{Resolver: resolver, Message: "pkg.M"})
+	fields, err := d.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(fields) != 1 {
+		t.Fatalf("Decode() fields = %+v, want one field", fields)
+	}
+	if _, ok := fields[0].Value.(Fields); ok {
+		t.Fatalf("Decode() field 1 = %+v, rendered as a nested message despite being declared string", fields[0])
+	}
+}
+
+// TestDecodeSchemaResolvesFieldsInsideGroup guards against parseGroup
+// always recursing with an empty message name, which would leave fields
+// inside a proto2 group without a Desc even though the resolver knows the
+// group's declared message type.
+func TestDecodeSchemaResolvesFieldsInsideGroup(t *testing.T) {
+	proto := filepath.Join(t.TempDir(), "pkg.proto")
+	src := `syntax = "proto2"; package pkg; message M { optional group G = 1 { optional int32 x = 2; } }`
+	if err := os.WriteFile(proto, []byte(src), 0o644); err != nil {
+		t.Fatalf("writing test .proto: %v", err)
+	}
+	resolver, err := schema.Load(proto)
+	if err != nil {
+		t.Fatalf("schema.Load() error = %v", err)
+	}
+
+	inner := protowire.AppendTag(nil, 2, protowire.VarintType)
+	inner = protowire.AppendVarint(inner, 42)
+	data := protowire.AppendTag(nil, 1, protowire.StartGroupType)
+	data = append(data, inner...)
+	data = protowire.AppendTag(data, 1, protowire.EndGroupType)
+
+	d := NewDecoder(Options{Resolver: resolver, Message: "pkg.M"})
+	fields, err := d.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(fields) != 1 || fields[0].Desc == nil {
+		t.Fatalf("Decode() fields = %+v, want the group field itself to resolve against the schema", fields)
+	}
+	group, ok := fields[0].Value.(Group)
+	if !ok || len(group) != 1 {
+		t.Fatalf("Decode() field 1 value = %+v, want a Group with one field", fields[0].Value)
+	}
+	if group[0].Desc == nil {
+		t.Fatalf("Decode() group field %+v has no Desc, want it resolved against pkg.M.G", group[0])
+	}
+}
+
+func buildFrame(flags byte, payload []byte) []byte {
+	n := len(payload)
+	return append([]byte{flags, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}, payload...)
+}
+
+func TestDecodeFrames(t *testing.T) {
+	msg := protowire.AppendTag(nil, 1, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, 150)
+
+	t.Run("single uncompressed frame", func(t *testing.T) {
+		d := NewDecoder(Options{})
+		results, framed, err := d.DecodeFrames(buildFrame(0x00, msg), "gzip")
+		if err != nil {
+			t.Fatalf("DecodeFrames() error = %v", err)
+		}
+		if !framed {
+			t.Fatal("DecodeFrames() framed = false, want true")
+		}
+		if len(results) != 1 || len(results[0].Fields) != 1 {
+			t.Fatalf("DecodeFrames() results = %+v, want one frame with one field", results)
+		}
+	})
+
+	t.Run("message then trailer frame", func(t *testing.T) {
+		trailer := []byte("grpc-status: 0\r\ngrpc-message: OK\r\n")
+		data := append(buildFrame(0x00, msg), buildFrame(0x80, trailer)...)
+
+		d := NewDecoder(Options{})
+		results, framed, err := d.DecodeFrames(data, "gzip")
+		if err != nil {
+			t.Fatalf("DecodeFrames() error = %v", err)
+		}
+		if !framed || len(results) != 2 {
+			t.Fatalf("DecodeFrames() framed=%v results=%+v, want 2 framed results", framed, results)
+		}
+		if !results[1].Trailer || results[1].Trailers["grpc-status"] != "0" {
+			t.Fatalf("DecodeFrames() trailer frame = %+v, want grpc-status: 0", results[1])
+		}
+	})
+
+	t.Run("truncated frame header falls back to unframed", func(t *testing.T) {
+		d := NewDecoder(Options{})
+		results, framed, err := d.DecodeFrames(msg, "gzip")
+		if err != nil {
+			t.Fatalf("DecodeFrames() error = %v", err)
+		}
+		if framed {
+			t.Fatal("DecodeFrames() framed = true, want false for non-frame-shaped input")
+		}
+		if len(results) != 1 || len(results[0].Fields) != 1 {
+			t.Fatalf("DecodeFrames() results = %+v, want a single unframed result", results)
+		}
+	})
+
+	t.Run("frame length exceeds remaining data falls back to unframed", func(t *testing.T) {
+		bad := []byte{0x00, 0x00, 0x00, 0x00, 0xff}
+		d := NewDecoder(Options{})
+		_, framed, err := d.DecodeFrames(bad, "gzip")
+		if framed {
+			t.Fatal("DecodeFrames() framed = true, want false")
+		}
+		if err == nil {
+			t.Fatal("DecodeFrames() error = nil, want an error decoding the fallback as an unframed message")
+		}
+	})
+
+	t.Run("max bytes enforced before framing", func(t *testing.T) {
+		d := NewDecoder(Options{MaxBytes: 2})
+		_, _, err := d.DecodeFrames(buildFrame(0x00, msg), "gzip")
+		if err == nil || !strings.Contains(err.Error(), "exceeds max bytes") {
+			t.Fatalf("DecodeFrames() error = %v, want it to mention exceeding max bytes", err)
+		}
+	})
+}
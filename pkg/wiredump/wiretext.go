@@ -0,0 +1,65 @@
+package wiredump
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"protobuf-decode/wiretext"
+)
+
+// ToWiretext converts decoded Fields into wiretext.Field values so they can
+// be rendered with wiretext.Marshal.
+func ToWiretext(fields Fields) []wiretext.Field {
+	out := make([]wiretext.Field, len(fields))
+	for i, f := range fields {
+		wf := wiretext.Field{Tag: f.Tag, Type: f.Type}
+		switch v := f.Value.(type) {
+		case VarintValue:
+			wf.Value = v.Raw
+		case Fixed32Value:
+			wf.Value = v.Raw
+		case Fixed64Value:
+			wf.Value = v.Raw
+		case Fields:
+			wf.Value = ToWiretext(v)
+		case Group:
+			wf.Value = wiretext.Group(ToWiretext(Fields(v)))
+		case string:
+			wf.Value = []byte(v)
+		case []byte:
+			wf.Value = v
+		case PackedRepeated:
+			wf.Value = v.Raw
+		case Candidates:
+			wf.Value = v.Raw
+		}
+		out[i] = wf
+	}
+	return out
+}
+
+// FromWiretext converts wiretext.Field values parsed from DSL text back into
+// Fields, ready for Fields.Encode.
+func FromWiretext(fields []wiretext.Field) Fields {
+	out := make(Fields, len(fields))
+	for i, f := range fields {
+		field := Field{Tag: f.Tag, Type: f.Type}
+		switch v := f.Value.(type) {
+		case uint64:
+			if f.Type == protowire.Fixed64Type {
+				field.Value = Fixed64Value{Raw: v}
+			} else {
+				field.Value = VarintValue{Raw: v}
+			}
+		case uint32:
+			field.Value = Fixed32Value{Raw: v}
+		case []byte:
+			field.Value = v
+		case []wiretext.Field:
+			field.Value = FromWiretext(v)
+		case wiretext.Group:
+			field.Value = Group(FromWiretext(v))
+		}
+		out[i] = field
+	}
+	return out
+}
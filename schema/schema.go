@@ -0,0 +1,126 @@
+// Package schema resolves Protobuf wire-format fields against a compiled
+// FileDescriptorSet or a parsed .proto file, so that a decoder can print
+// declared field names, cardinality, and scalar types instead of bare tag
+// numbers and wire types.
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Descriptor is the schema information resolved for a single decoded field.
+type Descriptor struct {
+	Name     string
+	Repeated bool
+	Type     descriptorpb.FieldDescriptorProto_Type
+	// MessageType is the fully qualified name of the nested message type,
+	// set only when Type is TYPE_MESSAGE or TYPE_GROUP.
+	MessageType string
+}
+
+// Resolver resolves wire-format field numbers within a named message type
+// to their declared Descriptor.
+type Resolver struct {
+	messages map[string]*descriptorpb.DescriptorProto
+}
+
+// Load builds a Resolver from path, which may be either a compiled
+// FileDescriptorSet (as produced by `protoc --descriptor_set_out=`) or a
+// single .proto source file.
+func Load(path string) (*Resolver, error) {
+	if strings.HasSuffix(path, ".proto") {
+		return loadFromProto(path)
+	}
+	return loadFromDescriptorSet(path)
+}
+
+func loadFromDescriptorSet(path string) (*Resolver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading descriptor set %s: %w", path, err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parsing descriptor set %s: %w", path, err)
+	}
+	return newResolver(set.GetFile()), nil
+}
+
+func loadFromProto(path string) (*Resolver, error) {
+	parser := protoparse.Parser{ImportPaths: []string{filepath.Dir(path)}}
+	fds, err := parser.ParseFiles(filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	files := make([]*descriptorpb.FileDescriptorProto, len(fds))
+	for i, fd := range fds {
+		files[i] = fd.AsFileDescriptorProto()
+	}
+	return newResolver(files), nil
+}
+
+func newResolver(files []*descriptorpb.FileDescriptorProto) *Resolver {
+	r := &Resolver{messages: make(map[string]*descriptorpb.DescriptorProto)}
+	for _, f := range files {
+		r.indexMessages(f.GetPackage(), f.GetMessageType())
+	}
+	return r
+}
+
+func (r *Resolver) indexMessages(scope string, msgs []*descriptorpb.DescriptorProto) {
+	for _, m := range msgs {
+		full := m.GetName()
+		if scope != "" {
+			full = scope + "." + full
+		}
+		r.messages[full] = m
+		r.indexMessages(full, m.GetNestedType())
+	}
+}
+
+// Field returns the declared Descriptor for tag within message. The message
+// name must be fully qualified (e.g. "mypkg.MyMessage"); a leading "." is
+// accepted and ignored, and a bare name without its package is not found. It reports
+// false if the message or field is not known to the schema.
+func (r *Resolver) Field(message string, tag protowire.Number) (Descriptor, bool) {
+	m, ok := r.messages[strings.TrimPrefix(message, ".")]
+	if !ok {
+		return Descriptor{}, false
+	}
+	for _, f := range m.GetField() {
+		if protowire.Number(f.GetNumber()) != tag {
+			continue
+		}
+		d := Descriptor{
+			Name:     f.GetName(),
+			Repeated: f.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED,
+			Type:     f.GetType(),
+		}
+		if t := f.GetType(); t == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE || t == descriptorpb.FieldDescriptorProto_TYPE_GROUP {
+			d.MessageType = strings.TrimPrefix(f.GetTypeName(), ".")
+		}
+		return d, true
+	}
+	return Descriptor{}, false
+}
+
+// HasMessage reports whether message is known to the schema.
+func (r *Resolver) HasMessage(message string) bool {
+	_, ok := r.messages[strings.TrimPrefix(message, ".")]
+	return ok
+}
+
+// TypeName renders a FieldDescriptorProto_Type the way .proto source would
+// spell it, e.g. TYPE_INT32 -> "int32".
+func TypeName(t descriptorpb.FieldDescriptorProto_Type) string {
+	name := strings.ToLower(strings.TrimPrefix(t.String(), "TYPE_"))
+	return name
+}
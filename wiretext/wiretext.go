@@ -0,0 +1,367 @@
+// Package wiretext implements a small protopack-style textual DSL for
+// Protobuf wire-format data, so a decoded message can be dumped to text,
+// hand-edited, and re-serialized back to wire bytes. The syntax mirrors
+// Google's internal protopack tool:
+//
+//	Tag(1, VarintType), Varint(150)
+//	Tag(3, BytesType), LengthPrefix{
+//	  Tag(1, VarintType), Varint(10)
+//	}
+//	Tag(4, GroupType), Group{
+//	  Tag(1, VarintType), Varint(10)
+//	}
+package wiretext
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field is a single DSL entry: a tag/wire-type pair and its value. Value is
+// one of uint64 (Varint or Fixed64), uint32 (Fixed32), []byte (Bytes),
+// []Field (LengthPrefix holding a nested message's fields), or Group (a
+// proto2 StartGroup/EndGroup pair's fields).
+type Field struct {
+	Tag   protowire.Number
+	Type  protowire.Type
+	Value interface{}
+}
+
+// Group is the value of a Field whose Type is protowire.StartGroupType. It
+// is a distinct type from []Field so Marshal/Unmarshal can tell a group
+// apart from a LengthPrefix-delimited nested message sharing the same
+// representation.
+type Group []Field
+
+var wireTypeNames = map[protowire.Type]string{
+	protowire.VarintType:     "VarintType",
+	protowire.Fixed32Type:    "Fixed32Type",
+	protowire.Fixed64Type:    "Fixed64Type",
+	protowire.BytesType:      "BytesType",
+	protowire.StartGroupType: "GroupType",
+}
+
+var wireTypeValues = map[string]protowire.Type{
+	"VarintType":  protowire.VarintType,
+	"Fixed32Type": protowire.Fixed32Type,
+	"Fixed64Type": protowire.Fixed64Type,
+	"BytesType":   protowire.BytesType,
+	"GroupType":   protowire.StartGroupType,
+}
+
+// Marshal renders fields as DSL text, one field per line.
+func Marshal(fields []Field) string {
+	var b strings.Builder
+	marshalFields(&b, fields, "")
+	return b.String()
+}
+
+func marshalFields(b *strings.Builder, fields []Field, indent string) {
+	for _, f := range fields {
+		b.WriteString(fmt.Sprintf("%sTag(%d, %s), ", indent, f.Tag, wireTypeNames[f.Type]))
+		switch v := f.Value.(type) {
+		case uint64:
+			if f.Type == protowire.Fixed64Type {
+				b.WriteString(fmt.Sprintf("Fixed64(%d)\n", v))
+			} else {
+				b.WriteString(fmt.Sprintf("Varint(%d)\n", v))
+			}
+		case uint32:
+			b.WriteString(fmt.Sprintf("Fixed32(%d)\n", v))
+		case []byte:
+			b.WriteString(fmt.Sprintf("Bytes(%s)\n", hex.EncodeToString(v)))
+		case []Field:
+			b.WriteString("LengthPrefix{\n")
+			marshalFields(b, v, indent+"  ")
+			b.WriteString(indent + "}\n")
+		case Group:
+			b.WriteString("Group{\n")
+			marshalFields(b, v, indent+"  ")
+			b.WriteString(indent + "}\n")
+		default:
+			b.WriteString(fmt.Sprintf("/* unsupported value %T */\n", v))
+		}
+	}
+}
+
+// Unmarshal parses DSL text of the form produced by Marshal back into
+// Fields.
+func Unmarshal(s string) ([]Field, error) {
+	p := &parser{lex: newLexer(s)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	fields, err := p.parseFields(tokEOF)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// --- lexer ---
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokNumber
+	tokLParen
+	tokRParen
+	tokLBrace
+	tokRBrace
+	tokComma
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type lexer struct {
+	s   string
+	pos int
+}
+
+func newLexer(s string) *lexer {
+	return &lexer{s: s}
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.s) && isSpace(l.s[l.pos]) {
+		l.pos++
+	}
+	if l.pos >= len(l.s) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.s[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen, pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen, pos: start}, nil
+	case c == '{':
+		l.pos++
+		return token{kind: tokLBrace, pos: start}, nil
+	case c == '}':
+		l.pos++
+		return token{kind: tokRBrace, pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokComma, pos: start}, nil
+	case isIdentStart(c):
+		for l.pos < len(l.s) && isIdentPart(l.s[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokIdent, text: l.s[start:l.pos], pos: start}, nil
+	case isDigit(c) || c == '-':
+		l.pos++
+		for l.pos < len(l.s) && (isIdentPart(l.s[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: l.s[start:l.pos], pos: start}, nil
+	default:
+		return token{}, fmt.Errorf("wiretext: unexpected character %q at offset %d", c, start)
+	}
+}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+func isIdentPart(c byte) bool { return isIdentStart(c) || isDigit(c) || c == 'x' }
+
+// --- parser ---
+
+type parser struct {
+	lex *lexer
+	cur token
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.cur = t
+	return nil
+}
+
+func (p *parser) expect(k tokenKind) (token, error) {
+	if p.cur.kind != k {
+		return token{}, fmt.Errorf("wiretext: unexpected token %q at offset %d", p.cur.text, p.cur.pos)
+	}
+	t := p.cur
+	if err := p.advance(); err != nil {
+		return token{}, err
+	}
+	return t, nil
+}
+
+// parseFields parses a comma-separated sequence of "Tag(n, Type), Value"
+// entries until it sees stopAt (tokEOF at top level, tokRBrace inside a
+// LengthPrefix block).
+func (p *parser) parseFields(stopAt tokenKind) ([]Field, error) {
+	var fields []Field
+	for p.cur.kind != stopAt {
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+		if p.cur.kind == tokComma {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fields, nil
+}
+
+func (p *parser) parseField() (Field, error) {
+	ident, err := p.expect(tokIdent)
+	if err != nil {
+		return Field{}, err
+	}
+	if ident.text != "Tag" {
+		return Field{}, fmt.Errorf("wiretext: expected Tag(...) at offset %d, got %q", ident.pos, ident.text)
+	}
+	if _, err := p.expect(tokLParen); err != nil {
+		return Field{}, err
+	}
+	tagTok, err := p.expect(tokNumber)
+	if err != nil {
+		return Field{}, err
+	}
+	tag, err := strconv.ParseUint(tagTok.text, 10, 32)
+	if err != nil {
+		return Field{}, fmt.Errorf("wiretext: invalid tag %q at offset %d: %w", tagTok.text, tagTok.pos, err)
+	}
+	if _, err := p.expect(tokComma); err != nil {
+		return Field{}, err
+	}
+	typeTok, err := p.expect(tokIdent)
+	if err != nil {
+		return Field{}, err
+	}
+	wireType, ok := wireTypeValues[typeTok.text]
+	if !ok {
+		return Field{}, fmt.Errorf("wiretext: unknown wire type %q at offset %d", typeTok.text, typeTok.pos)
+	}
+	if _, err := p.expect(tokRParen); err != nil {
+		return Field{}, err
+	}
+	if _, err := p.expect(tokComma); err != nil {
+		return Field{}, err
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return Field{}, err
+	}
+	return Field{Tag: protowire.Number(tag), Type: wireType, Value: value}, nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	ident, err := p.expect(tokIdent)
+	if err != nil {
+		return nil, err
+	}
+	switch ident.text {
+	case "Varint", "Fixed64":
+		if _, err := p.expect(tokLParen); err != nil {
+			return nil, err
+		}
+		numTok, err := p.expect(tokNumber)
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.ParseUint(numTok.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("wiretext: invalid integer %q at offset %d: %w", numTok.text, numTok.pos, err)
+		}
+		if _, err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return v, nil
+
+	case "Fixed32":
+		if _, err := p.expect(tokLParen); err != nil {
+			return nil, err
+		}
+		numTok, err := p.expect(tokNumber)
+		if err != nil {
+			return nil, err
+		}
+		v, err := strconv.ParseUint(numTok.text, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("wiretext: invalid integer %q at offset %d: %w", numTok.text, numTok.pos, err)
+		}
+		if _, err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return uint32(v), nil
+
+	case "Bytes":
+		if _, err := p.expect(tokLParen); err != nil {
+			return nil, err
+		}
+		b := []byte{}
+		if p.cur.kind != tokRParen {
+			if p.cur.kind != tokIdent && p.cur.kind != tokNumber {
+				return nil, fmt.Errorf("wiretext: expected hex bytes at offset %d, got %q", p.cur.pos, p.cur.text)
+			}
+			hexTok := p.cur
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			b, err = hex.DecodeString(hexTok.text)
+			if err != nil {
+				return nil, fmt.Errorf("wiretext: invalid hex bytes %q at offset %d: %w", hexTok.text, hexTok.pos, err)
+			}
+		}
+		if _, err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return b, nil
+
+	case "LengthPrefix":
+		if _, err := p.expect(tokLBrace); err != nil {
+			return nil, err
+		}
+		fields, err := p.parseFields(tokRBrace)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRBrace); err != nil {
+			return nil, err
+		}
+		return fields, nil
+
+	case "Group":
+		if _, err := p.expect(tokLBrace); err != nil {
+			return nil, err
+		}
+		fields, err := p.parseFields(tokRBrace)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRBrace); err != nil {
+			return nil, err
+		}
+		return Group(fields), nil
+
+	default:
+		return nil, fmt.Errorf("wiretext: unknown value expression %q at offset %d", ident.text, ident.pos)
+	}
+}
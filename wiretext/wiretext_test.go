@@ -0,0 +1,41 @@
+package wiretext
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TestMarshalUnmarshalGroupRoundTrip guards against a decoded Group field
+// losing its wire type name on Marshal (empty GroupType) and then failing
+// to re-parse on Unmarshal, breaking the decode-edit-encode workflow for
+// proto2 messages containing groups.
+func TestMarshalUnmarshalGroupRoundTrip(t *testing.T) {
+	fields := []Field{
+		{
+			Tag:  1,
+			Type: protowire.StartGroupType,
+			Value: Group{
+				{Tag: 2, Type: protowire.VarintType, Value: uint64(42)},
+			},
+		},
+	}
+
+	text := Marshal(fields)
+	if !strings.Contains(text, "GroupType") {
+		t.Fatalf("Marshal() = %q, want it to mention GroupType", text)
+	}
+
+	got, err := Unmarshal(text)
+	if err != nil {
+		t.Fatalf("Unmarshal(%q) error = %v", text, err)
+	}
+	if len(got) != 1 || got[0].Tag != 1 || got[0].Type != protowire.StartGroupType {
+		t.Fatalf("Unmarshal(%q) = %+v, want the original group field back", text, got)
+	}
+	group, ok := got[0].Value.(Group)
+	if !ok || len(group) != 1 || group[0].Value.(uint64) != 42 {
+		t.Fatalf("Unmarshal(%q) field 1 value = %+v, want a Group with Varint(42)", text, got[0].Value)
+	}
+}